@@ -0,0 +1,70 @@
+package mux
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// builtinConstraints maps the named shortcuts accepted inside "{name:...}"
+// to the regular expression they expand to.
+var builtinConstraints = map[string]string{
+	"int":    `[0-9]+`,
+	"uuid":   `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	"semver": `v?[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?`,
+}
+
+// constraintCache holds the compiled *regexp.Regexp for each constraint
+// string seen so far, so Match doesn't recompile it on every request.
+var constraintCache sync.Map // map[string]*regexp.Regexp
+
+// satisfiesConstraint reports whether value satisfies constraint, which is
+// either empty (always satisfied), one of builtinConstraints, or a regular
+// expression. An invalid regular expression never satisfies.
+func satisfiesConstraint(constraint, value string) bool {
+	if constraint == "" {
+		return true
+	}
+	re, err := compileConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
+
+func compileConstraint(constraint string) (*regexp.Regexp, error) {
+	if cached, ok := constraintCache.Load(constraint); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	pattern := constraint
+	if expanded, ok := builtinConstraints[constraint]; ok {
+		pattern = expanded
+	}
+
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, err
+	}
+	constraintCache.Store(constraint, re)
+	return re, nil
+}
+
+// Int parses the value captured for key as a base-10 integer.
+func (vars Vars) Int(key string) (int64, error) {
+	n, err := strconv.ParseInt(vars.Get(key), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("mux: variable %q is not an integer: %w", key, err)
+	}
+	return n, nil
+}
+
+// UUID returns the value captured for key if it is a well-formed UUID.
+func (vars Vars) UUID(key string) (string, error) {
+	v := vars.Get(key)
+	if !satisfiesConstraint("uuid", v) {
+		return "", fmt.Errorf("mux: variable %q is not a uuid", key)
+	}
+	return v, nil
+}
@@ -0,0 +1,56 @@
+package mux
+
+import "testing"
+
+func TestMatchConstraint(t *testing.T) {
+	cases := []struct {
+		pattern, text string
+		match         bool
+		vars          Vars
+	}{
+		{"/users/{id:int}", "/users/42", true, Vars{{"id", "42"}}},
+		{"/users/{id:int}", "/users/abc", false, Vars{}},
+		{"/users/{id:[0-9]+}", "/users/007", true, Vars{{"id", "007"}}},
+		{"/posts/{slug:[a-z0-9-]+}", "/posts/Hello", false, Vars{}},
+		{"/v/{ver:semver}", "/v/1.2.3", true, Vars{{"ver", "1.2.3"}}},
+		{"/v/{ver:semver}", "/v/latest", false, Vars{}},
+		{
+			"a{1:[a-z]+}b{2:int}",
+			"axyzb12",
+			true,
+			Vars{{"1", "xyz"}, {"2", "12"}},
+		},
+		{"a{1:[a-z]+}b{2:int}", "axyzbxx", false, Vars{}},
+	}
+
+	for _, tc := range cases {
+		vs := Vars{}
+		ok := Match(tc.pattern, tc.text, &vs)
+		if ok != tc.match {
+			t.Errorf("Match(%#q, %#q) = %v, want %v", tc.pattern, tc.text, ok, tc.match)
+		}
+		equal(t, vs, tc.vars, "captured vars")
+	}
+}
+
+func TestVarsIntUUID(t *testing.T) {
+	vars := Vars{{"id", "42"}, {"bad", "nope"}, {"token", "550e8400-e29b-41d4-a716-446655440000"}}
+
+	n, err := vars.Int("id")
+	if err != nil || n != 42 {
+		t.Errorf("Int(%q) = %v, %v, want 42, nil", "id", n, err)
+	}
+
+	if _, err := vars.Int("bad"); err == nil {
+		t.Error("Int(\"bad\") = nil error, want error")
+	}
+
+	u, err := vars.UUID("token")
+	if err != nil || u != "550e8400-e29b-41d4-a716-446655440000" {
+		t.Errorf("UUID(%q) = %v, %v, want matching uuid, nil", "token", u, err)
+	}
+
+	if _, err := vars.UUID("bad"); err == nil {
+		t.Error("UUID(\"bad\") = nil error, want error")
+	}
+}
@@ -0,0 +1,90 @@
+package mux
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures the Middleware returned by CORS.
+type CORSOptions struct {
+	AllowedOrigins   []string // "*" or exact origins
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS returns a Middleware that answers cross-origin requests per opts.
+// Preflight OPTIONS requests (those carrying both an Origin and an
+// Access-Control-Request-Method header) from an allowed origin are answered
+// directly by the middleware instead of reaching the wrapped handler;
+// requests without an Origin header or from an origin not in
+// AllowedOrigins are passed to the wrapped handler unchanged. When wrapping a
+// Method, Access-Control-Allow-Methods is derived from Method.Methods,
+// the same sorted list that otherwise populates the Allow header, so
+// preflight responses never need a separately maintained method list.
+func CORS(opts CORSOptions) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			origin := req.Header.Get("Origin")
+			if origin == "" || !opts.originAllowed(origin) {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			header := rw.Header()
+			header.Add("Vary", "Origin")
+			if opts.AllowCredentials {
+				header.Set("Access-Control-Allow-Origin", origin)
+				header.Set("Access-Control-Allow-Credentials", "true")
+			} else if opts.allowsAnyOrigin() {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if len(opts.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(opts.ExposedHeaders, ", "))
+			}
+
+			if req.Method != http.MethodOptions || req.Header.Get("Access-Control-Request-Method") == "" {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			methods := "*"
+			if route, ok := next.(Method); ok {
+				methods = strings.Join(route.Methods(), ", ")
+			}
+			header.Set("Access-Control-Allow-Methods", methods)
+			if len(opts.AllowedHeaders) > 0 {
+				header.Set("Access-Control-Allow-Headers", strings.Join(opts.AllowedHeaders, ", "))
+			} else if reqHeaders := req.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", reqHeaders)
+			}
+			if opts.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			rw.WriteHeader(http.StatusOK)
+		})
+	}
+}
+
+func (opts CORSOptions) originAllowed(origin string) bool {
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (opts CORSOptions) allowsAnyOrigin() bool {
+	for _, o := range opts.AllowedOrigins {
+		if o == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	route := Method{
+		http.MethodGet:  serve(200),
+		http.MethodPost: serve(200),
+	}
+	mw := CORS(CORSOptions{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         10 * time.Minute,
+	})
+	handler := mw(route)
+
+	t.Run("simple request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://example.com")
+		rw := httptest.NewRecorder()
+
+		handler.ServeHTTP(rw, req)
+
+		equal(t, rw.Code, http.StatusOK, "status code")
+		equal(t, rw.Header().Get("Access-Control-Allow-Origin"), "https://example.com", "allow-origin")
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		rw := httptest.NewRecorder()
+
+		handler.ServeHTTP(rw, req)
+
+		equal(t, rw.Code, http.StatusOK, "status code")
+		equal(t, rw.Header().Get("Access-Control-Allow-Methods"), "GET, OPTIONS, POST", "allow-methods")
+		equal(t, rw.Header().Get("Access-Control-Allow-Headers"), "Content-Type", "allow-headers")
+		equal(t, rw.Header().Get("Access-Control-Max-Age"), "600", "max-age")
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://evil.example")
+		rw := httptest.NewRecorder()
+
+		handler.ServeHTTP(rw, req)
+
+		equal(t, rw.Header().Get("Access-Control-Allow-Origin"), "", "allow-origin")
+	})
+}
+
+func TestCORSMethodWith(t *testing.T) {
+	route := Method{
+		http.MethodGet:  serve(http.StatusOK),
+		http.MethodPost: serve(http.StatusOK),
+	}.With(CORS(CORSOptions{AllowedOrigins: []string{"https://example.com"}}))
+
+	t.Run("simple request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://example.com")
+		rw := httptest.NewRecorder()
+
+		route.ServeHTTP(rw, req)
+
+		equal(t, rw.Code, http.StatusOK, "status code")
+		equal(t, rw.Header().Get("Access-Control-Allow-Origin"), "https://example.com", "allow-origin")
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", "https://example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		rw := httptest.NewRecorder()
+
+		route.ServeHTTP(rw, req)
+
+		equal(t, rw.Code, http.StatusOK, "status code")
+		equal(t, rw.Header().Get("Access-Control-Allow-Methods"), "GET, OPTIONS, POST", "allow-methods")
+	})
+}
+
+func TestCORSRouter(t *testing.T) {
+	rt := NewRouter()
+	rt.Use(CORS(CORSOptions{AllowedOrigins: []string{"https://example.com"}}))
+	rt.Get("/widgets/{id}", serve(http.StatusOK))
+	rt.Put("/widgets/{id}", serve(http.StatusOK))
+
+	req := httptest.NewRequest(http.MethodOptions, "/widgets/1", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	rw := httptest.NewRecorder()
+
+	rt.ServeHTTP(rw, req)
+
+	equal(t, rw.Code, http.StatusOK, "status code")
+	equal(t, rw.Header().Get("Access-Control-Allow-Methods"), "GET, OPTIONS, PUT", "allow-methods")
+}
@@ -0,0 +1,125 @@
+package mux
+
+import (
+	"fmt"
+	"strings"
+)
+
+// token is one piece of a compiled pattern segment.
+type token struct {
+	kind byte // 'l' literal, '?', '*', or '{'
+	name string
+}
+
+// segment is a single '/'-delimited piece of a compiled pattern. literal
+// segments (those with no wildcard tokens) can be compared with a plain
+// string equality instead of running the token-by-token matcher.
+type segment struct {
+	raw     string // original pattern text for this segment
+	tokens  []token
+	literal bool
+}
+
+// Matcher is a pattern precompiled by Compile. Unlike Match, which
+// reparses the whole pattern on every call, a Matcher splits the pattern
+// into segments once: fully literal patterns collapse to a single string
+// comparison, literal segments are compared with plain string equality,
+// and only segments containing wildcards are rerun through Match's
+// backtracking matcher.
+type Matcher struct {
+	pattern  string
+	segments []segment
+	vars     []string
+	literal  bool // every segment is literal
+}
+
+// Compile parses pattern once into a Matcher that can be reused across
+// many calls to Match without resplitting or revalidating pattern each time.
+func Compile(pattern string) (*Matcher, error) {
+	m := &Matcher{pattern: pattern, literal: true}
+	for _, part := range strings.Split(pattern, "/") {
+		seg, err := compileSegment(part)
+		if err != nil {
+			return nil, err
+		}
+		m.literal = m.literal && seg.literal
+		m.segments = append(m.segments, seg)
+		for _, tok := range seg.tokens {
+			if tok.kind == '{' {
+				m.vars = append(m.vars, tok.name)
+			}
+		}
+	}
+	return m, nil
+}
+
+// compileSegment scans a single '/'-delimited pattern segment into tokens.
+func compileSegment(part string) (segment, error) {
+	seg := segment{raw: part, literal: true}
+	i := 0
+	for i < len(part) {
+		switch c := part[i]; c {
+		case '*', '?':
+			seg.literal = false
+			seg.tokens = append(seg.tokens, token{kind: c})
+			i++
+		case '{':
+			end := strings.IndexByte(part[i+1:], '}')
+			if end < 0 {
+				return segment{}, fmt.Errorf("mux: unterminated variable in pattern %q", part)
+			}
+			name, _ := splitConstraint(part[i+1 : i+1+end])
+			if name == "" {
+				return segment{}, fmt.Errorf("mux: empty variable name in pattern %q", part)
+			}
+			seg.literal = false
+			seg.tokens = append(seg.tokens, token{kind: '{', name: name})
+			i += end + 2
+		default:
+			seg.tokens = append(seg.tokens, token{kind: 'l'})
+			i++
+		}
+	}
+	return seg, nil
+}
+
+// Match reports whether text matches the compiled pattern, extracting
+// named variables to vars exactly as Match would.
+func (m *Matcher) Match(text string, vars *Vars) bool {
+	if m.literal {
+		if text != m.pattern {
+			vars.Reset()
+			return false
+		}
+		return true
+	}
+	parts := strings.Split(text, "/")
+	if len(parts) != len(m.segments) {
+		vars.Reset()
+		return false
+	}
+	for i, seg := range m.segments {
+		if seg.literal {
+			if seg.raw != parts[i] {
+				vars.Reset()
+				return false
+			}
+			continue
+		}
+		if !match(seg.raw, parts[i], '/', vars) {
+			return false
+		}
+	}
+	return true
+}
+
+// Vars returns the names of the variables pattern declares, in the order
+// they appear.
+func (m *Matcher) Vars() []string {
+	return m.vars
+}
+
+// String returns the original pattern passed to Compile.
+func (m *Matcher) String() string {
+	return m.pattern
+}
@@ -0,0 +1,40 @@
+package mux
+
+import "testing"
+
+func TestCompile(t *testing.T) {
+	cases := []struct {
+		pattern, text string
+		match         bool
+		vars          Vars
+	}{
+		{"abc", "abc", true, Vars{}},
+		{"abc", "abd", false, Vars{}},
+		{"/users/{id}", "/users/42", true, Vars{{"id", "42"}}},
+		{"/users/{id}", "/users", false, Vars{}},
+		{"/users/{id:int}", "/users/xx", false, Vars{}},
+		{"a*b?c", "axxxbyc", true, Vars{}},
+	}
+
+	for _, tc := range cases {
+		m, err := Compile(tc.pattern)
+		if err != nil {
+			t.Fatalf("Compile(%#q): %v", tc.pattern, err)
+		}
+		vs := Vars{}
+		ok := m.Match(tc.text, &vs)
+		if ok != tc.match {
+			t.Errorf("Compile(%#q).Match(%#q) = %v, want %v", tc.pattern, tc.text, ok, tc.match)
+		}
+		equal(t, vs, tc.vars, "captured vars")
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	if _, err := Compile("/users/{id"); err == nil {
+		t.Error("Compile: expected error for unterminated variable")
+	}
+	if _, err := Compile("/users/{}"); err == nil {
+		t.Error("Compile: expected error for empty variable name")
+	}
+}
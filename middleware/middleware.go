@@ -0,0 +1,106 @@
+// Package middleware provides mux.Middleware implementations for common
+// cross-cutting concerns: panic recovery, request IDs, client IP resolution,
+// and request logging.
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Recoverer recovers from panics in handlers further down the chain,
+// responding with 500 instead of letting the panic reach the server and
+// close the connection.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				log.Printf("mux: panic serving %s %s: %v", req.Method, req.URL.Path, err)
+				http.Error(rw, "500 internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(rw, req)
+	})
+}
+
+// requestIDKey is the context key under which RequestID stores the
+// generated ID.
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID generated by RequestID for
+// req's context, if any.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// RequestID generates a random ID for every request, attaches it to the
+// request context (retrievable with RequestIDFromContext), and sets it on
+// the response as X-Request-Id.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		id := newRequestID()
+		rw.Header().Set("X-Request-Id", id)
+		ctx := context.WithValue(req.Context(), requestIDKey{}, id)
+		next.ServeHTTP(rw, req.WithContext(ctx))
+	})
+}
+
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// RealIP overwrites req.RemoteAddr with the client address reported by the
+// X-Forwarded-For or X-Real-IP headers, preferring X-Forwarded-For's
+// left-most (original client) entry. It should only be installed behind a
+// trusted proxy, since these headers are otherwise client-controlled.
+func RealIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if ip := realIP(req); ip != "" {
+			req.RemoteAddr = ip
+		}
+		next.ServeHTTP(rw, req)
+	})
+}
+
+func realIP(req *http.Request) string {
+	if xff := req.Header.Get("X-Forwarded-For"); xff != "" {
+		if i := strings.IndexByte(xff, ','); i >= 0 {
+			xff = xff[:i]
+		}
+		return strings.TrimSpace(xff)
+	}
+	return req.Header.Get("X-Real-IP")
+}
+
+// Logger logs one line per request: method, path, status code, and
+// duration.
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: rw, status: http.StatusOK}
+		next.ServeHTTP(sw, req)
+		log.Printf("%s %s %d %s", req.Method, req.URL.Path, sw.status, time.Since(start))
+	})
+}
+
+// statusWriter records the status code passed to WriteHeader so Logger can
+// report it after the handler returns.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
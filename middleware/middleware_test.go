@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecoverer(t *testing.T) {
+	h := Recoverer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		panic("boom")
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("Code = %d, want %d", rw.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	var got string
+	h := RequestID(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		got, _ = RequestIDFromContext(req.Context())
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got == "" {
+		t.Fatal("RequestIDFromContext returned no ID")
+	}
+	if rw.Header().Get("X-Request-Id") != got {
+		t.Errorf("X-Request-Id header = %q, want %q", rw.Header().Get("X-Request-Id"), got)
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	cases := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"forwarded-for", map[string]string{"X-Forwarded-For": "203.0.113.1, 10.0.0.1"}, "203.0.113.1"},
+		{"real-ip", map[string]string{"X-Real-IP": "203.0.113.2"}, "203.0.113.2"},
+		{"none", nil, "192.0.2.1:1234"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			h := RealIP(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				got = req.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "192.0.2.1:1234"
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			if got != tc.want {
+				t.Errorf("RemoteAddr = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
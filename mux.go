@@ -17,19 +17,42 @@ type Vars []struct{ k, v string }
 //      { term }
 //  term:
 //      '*'         matches any sequence of non-/ characters
-//      '{' { variable-name } '}'
+//      '{' { variable-name } [ ':' constraint ] '}'
 //                  named variable (must be non-empty); matches any sequence
-//                  of non-/ characters
+//                  of non-/ characters, additionally satisfying constraint
+//                  if given
 //      '?'         matches any single non-/ character
 //      c           matches character c (c != '*', '?', '{')
 //
 //  variable-name:
-//      c           matches character c (c != '}')
+//      c           matches character c (c != '}', ':')
+//
+//  constraint:
+//      'int' | 'uuid' | 'semver'
+//                  builtin shortcuts
+//      regexp      any other constraint is compiled as a regular expression
 //
 // Match requires pattern to match all of text, not just a substring.
 // Named variables defined in the pattern are extracted to vars.
 func Match(pattern, text string, vars *Vars) bool {
-	key := ""
+	return match(pattern, text, '/', vars)
+}
+
+// MatchHost is like Match but for host patterns: it uses the same
+// '*'/'?'/'{name}' syntax, except wildcards cannot skip '.' instead of '/'.
+func MatchHost(pattern, host string, vars *Vars) bool {
+	return match(pattern, host, '.', vars)
+}
+
+// match is the shared engine behind Match and MatchHost. sep is the
+// character variable-length wildcards ('*' and '{name}') cannot skip over.
+//
+// A '{name}' term may carry an optional ":constraint" suffix (e.g.
+// "{id:[0-9]+}"); the captured span must then additionally satisfy that
+// constraint (see satisfiesConstraint) or the term is treated as a mismatch,
+// same as a literal mismatch, and backtracking continues.
+func match(pattern, text string, sep byte, vars *Vars) bool {
+	var key, constraint, rawKey string
 	nx, vx := 0, 0
 	px, tx := 0, 0
 	nextPx := 0
@@ -39,15 +62,15 @@ func Match(pattern, text string, vars *Vars) bool {
 			switch c := pattern[px]; c {
 			default:
 				if tx < len(text) && text[tx] == c {
-					if px > 0 && pattern[px-1] == '}' {
-						vars.Set(key, text[vx:tx])
+					if px > 0 && pattern[px-1] == '}' && !commitVar(key, constraint, text[vx:tx], vars) {
+						break
 					}
 					px++
 					tx++
 					continue
 				}
 			case '?':
-				if tx < len(text) && text[tx] != '/' {
+				if tx < len(text) && text[tx] != sep {
 					_, n := utf8.DecodeRuneInString(text[tx:])
 					px += 1
 					tx += n
@@ -63,9 +86,10 @@ func Match(pattern, text string, vars *Vars) bool {
 					if nx < px {
 						vx = tx
 						nx = px + strings.IndexByte(pattern[px:], '}')
-						key = pattern[px:nx]
+						rawKey = pattern[px:nx]
+						key, constraint = splitConstraint(rawKey)
 					}
-					px += len(key) + 1
+					px += len(rawKey) + 1
 				}
 				continue
 			}
@@ -73,20 +97,40 @@ func Match(pattern, text string, vars *Vars) bool {
 		if nextTx <= len(text) {
 			px = nextPx
 			tx = nextTx
-			// Variable-length wildcards cannot skip /.
-			if (pattern[px] == '*' || pattern[px] == '{') && text[tx-1] != '/' {
+			// Variable-length wildcards cannot skip sep.
+			if (pattern[px] == '*' || pattern[px] == '{') && text[tx-1] != sep {
 				continue
 			}
 		}
 		vars.Reset()
 		return false
 	}
-	if px > 0 && pattern[px-1] == '}' {
-		vars.Set(key, text[vx:tx])
+	if px > 0 && pattern[px-1] == '}' && !commitVar(key, constraint, text[vx:tx], vars) {
+		vars.Reset()
+		return false
+	}
+	return true
+}
+
+// commitVar records value under key if it satisfies constraint, reporting
+// whether it did.
+func commitVar(key, constraint, value string, vars *Vars) bool {
+	if !satisfiesConstraint(constraint, value) {
+		return false
 	}
+	vars.Set(key, value)
 	return true
 }
 
+// splitConstraint splits a "{name}" term's raw content into its variable
+// name and optional ":constraint" suffix.
+func splitConstraint(raw string) (name, constraint string) {
+	if i := strings.IndexByte(raw, ':'); i >= 0 {
+		return raw[:i], raw[i+1:]
+	}
+	return raw, ""
+}
+
 // Set assigns the given value to the given key.
 func (vars *Vars) Set(key, value string) {
 	for i, p := range *vars {
@@ -139,17 +183,67 @@ func (route Method) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	allow := []string{http.MethodOptions}
-	for k := range route {
-		k = strings.ToUpper(k)
-		if k != http.MethodOptions {
-			allow = append(allow, k)
+	rw.Header().Set("Allow", strings.Join(route.Methods(), ", "))
+	if method != http.MethodOptions {
+		http.Error(rw, "405 method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Methods returns the sorted, upper-cased list of HTTP methods route
+// handles, always including OPTIONS; this is the same list that populates
+// the Allow header on a method miss.
+func (route Method) Methods() []string {
+	methods := []string{http.MethodOptions}
+	for method := range route {
+		method = strings.ToUpper(method)
+		if method != http.MethodOptions {
+			methods = append(methods, method)
 		}
 	}
-	sort.Strings(allow)
+	sort.Strings(methods)
+	return methods
+}
 
-	rw.Header().Set("Allow", strings.Join(allow, ", "))
-	if method != http.MethodOptions {
-		http.Error(rw, "405 method not allowed", http.StatusMethodNotAllowed)
+// With returns a copy of route, every one of whose methods (including
+// OPTIONS, synthesized if route didn't register one) runs mws, outermost
+// first, in front of route as a whole. Middleware sees route itself as
+// next, not one individual handler, so a Middleware such as CORS that
+// type-asserts next.(Method) to read Methods() still works, and so a
+// preflight OPTIONS request still reaches the middleware chain instead of
+// being answered by Method.ServeHTTP before mws ever runs.
+func (route Method) With(mws ...Middleware) Method {
+	wrapped := Chain(mws...)(route)
+	methods := route.Methods()
+	out := make(Method, len(methods))
+	for _, method := range methods {
+		out[method] = wrapped
+	}
+	return out
+}
+
+// Middleware wraps an http.Handler to produce another, typically adding
+// behavior before and/or after calling the wrapped handler.
+type Middleware func(http.Handler) http.Handler
+
+// Chain combines mws into a single Middleware that applies them in the
+// order given, so Chain(a, b)(h) behaves as a(b(h)).
+func Chain(mws ...Middleware) Middleware {
+	return func(h http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+		return h
+	}
+}
+
+// WrapFunc adapts fn, a plain function taking the request alongside the
+// next handler in the chain, into a Middleware. It saves callers from
+// writing out the http.HandlerFunc boilerplate for simple middleware that
+// has no setup to do before returning its handler.
+func WrapFunc(fn func(rw http.ResponseWriter, req *http.Request, next http.Handler)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			fn(rw, req, next)
+		})
 	}
 }
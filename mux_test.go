@@ -164,3 +164,51 @@ func TestMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				order = append(order, name)
+				h.ServeHTTP(rw, req)
+			})
+		}
+	}
+
+	h := Chain(mw("a"), mw("b"))(serve(200))
+	h.ServeHTTP(httptest.NewRecorder(), &http.Request{})
+
+	equal(t, order, []string{"a", "b"}, "call order")
+}
+
+func TestWrapFunc(t *testing.T) {
+	var got string
+	mw := WrapFunc(func(rw http.ResponseWriter, req *http.Request, next http.Handler) {
+		got = req.Header.Get("X-Test")
+		next.ServeHTTP(rw, req)
+	})
+
+	h := mw(serve(200))
+	req := &http.Request{Method: http.MethodGet, Header: http.Header{"X-Test": {"hi"}}}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	equal(t, got, "hi", "value seen by wrapped function")
+}
+
+func TestMethodWith(t *testing.T) {
+	var called []string
+	mw := func(name string) Middleware {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				called = append(called, name)
+				h.ServeHTTP(rw, req)
+			})
+		}
+	}
+
+	route := Get(serve(200)).With(mw("logged"))
+	route.ServeHTTP(httptest.NewRecorder(), &http.Request{Method: http.MethodGet})
+
+	equal(t, called, []string{"logged"}, "wrapped middleware")
+}
@@ -0,0 +1,140 @@
+package mux
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Route composes path, host, method and header predicates into a single
+// http.Handler, running each predicate in order and merging any Vars they
+// capture into the request context (retrievable with VarsFromContext).
+type Route struct {
+	pathPattern string
+	hostPattern string
+	schemes     map[string]bool
+	methods     map[string]bool
+	headers     [][2]string
+	handler     http.Handler
+}
+
+// NewRoute returns an empty Route ready for configuration.
+func NewRoute() *Route {
+	return &Route{}
+}
+
+// Path constrains the route to requests whose URL path matches pattern,
+// using the Match syntax.
+func (r *Route) Path(pattern string) *Route {
+	r.pathPattern = pattern
+	return r
+}
+
+// Host constrains the route to requests whose host matches pattern, using
+// the MatchHost syntax.
+func (r *Route) Host(pattern string) *Route {
+	r.hostPattern = pattern
+	return r
+}
+
+// Schemes constrains the route to the given URL schemes (e.g. "http",
+// "https").
+func (r *Route) Schemes(schemes ...string) *Route {
+	r.schemes = make(map[string]bool, len(schemes))
+	for _, s := range schemes {
+		r.schemes[strings.ToLower(s)] = true
+	}
+	return r
+}
+
+// Methods constrains the route to the given HTTP methods.
+func (r *Route) Methods(methods ...string) *Route {
+	r.methods = make(map[string]bool, len(methods))
+	for _, m := range methods {
+		r.methods[strings.ToUpper(m)] = true
+	}
+	return r
+}
+
+// Headers constrains the route to requests carrying the given header
+// name/value pairs.
+func (r *Route) Headers(pairs ...string) *Route {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		r.headers = append(r.headers, [2]string{pairs[i], pairs[i+1]})
+	}
+	return r
+}
+
+// Handler sets the handler to run once every predicate matches.
+func (r *Route) Handler(h http.Handler) *Route {
+	r.handler = h
+	return r
+}
+
+// ServeHTTP runs the route's predicates in order: path, host, scheme,
+// method, then headers. A path, host or scheme mismatch produces a 404, a
+// method mismatch a 405 with an Allow header, and a header mismatch a 406.
+// On success it merges the captured Vars into the request context and
+// calls the route's handler.
+func (r *Route) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	vars := Vars{}
+
+	if r.pathPattern != "" && !Match(r.pathPattern, req.URL.Path, &vars) {
+		http.NotFound(rw, req)
+		return
+	}
+
+	if r.hostPattern != "" {
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if !MatchHost(r.hostPattern, host, &vars) {
+			http.NotFound(rw, req)
+			return
+		}
+	}
+
+	if len(r.schemes) > 0 && !r.schemes[requestScheme(req)] {
+		http.NotFound(rw, req)
+		return
+	}
+
+	if len(r.methods) > 0 && !r.methods[strings.ToUpper(req.Method)] {
+		allow := make([]string, 0, len(r.methods))
+		for m := range r.methods {
+			allow = append(allow, m)
+		}
+		sort.Strings(allow)
+		rw.Header().Set("Allow", strings.Join(allow, ", "))
+		http.Error(rw, "405 method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	for _, h := range r.headers {
+		if req.Header.Get(h[0]) != h[1] {
+			http.Error(rw, "406 not acceptable", http.StatusNotAcceptable)
+			return
+		}
+	}
+
+	if len(vars) > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), varsContextKey{}, vars))
+	}
+	r.handler.ServeHTTP(rw, req)
+}
+
+// requestScheme reports the scheme a request arrived on, preferring the
+// scheme of an absolute-URI request target and otherwise inferring it from
+// whether the connection was TLS-terminated.
+func requestScheme(req *http.Request) string {
+	if req.URL.Scheme != "" {
+		return strings.ToLower(req.URL.Scheme)
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,82 @@
+package mux
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMatchHost(t *testing.T) {
+	cases := []struct {
+		pattern, host string
+		match         bool
+		vars          Vars
+	}{
+		{"api.example.com", "api.example.com", true, Vars{}},
+		{"{sub}.example.com", "api.example.com", true, Vars{{"sub", "api"}}},
+		{"{sub}.example.com", "a.b.example.com", false, Vars{}},
+		{"*.example.com", "a.b.example.com", false, Vars{}},
+	}
+
+	for _, tc := range cases {
+		vs := Vars{}
+		ok := MatchHost(tc.pattern, tc.host, &vs)
+		if ok != tc.match {
+			t.Errorf("MatchHost(%#q, %#q) = %v, want %v", tc.pattern, tc.host, ok, tc.match)
+		}
+		equal(t, vs, tc.vars, "captured vars")
+	}
+}
+
+func TestRoute(t *testing.T) {
+	route := NewRoute().
+		Path("/v1/{id}").
+		Host("{sub}.api.example.com").
+		Methods(http.MethodGet, http.MethodHead).
+		Headers("Content-Type", "application/json").
+		Handler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			writeVars(rw, req)
+		}))
+
+	newReq := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/v1/42", nil)
+		req.Host = "eu.api.example.com"
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	rw := httptest.NewRecorder()
+	route.ServeHTTP(rw, newReq(http.MethodGet))
+	equal(t, rw.Code, http.StatusOK, "status code")
+	equal(t, rw.Body.String(), "id=42 sub=eu", "captured vars")
+
+	rw = httptest.NewRecorder()
+	route.ServeHTTP(rw, newReq(http.MethodPost))
+	equal(t, rw.Code, http.StatusMethodNotAllowed, "status code for wrong method")
+
+	rw = httptest.NewRecorder()
+	badReq := newReq(http.MethodGet)
+	badReq.Header.Set("Content-Type", "text/plain")
+	route.ServeHTTP(rw, badReq)
+	equal(t, rw.Code, http.StatusNotAcceptable, "status code for wrong header")
+}
+
+func TestRouteSchemes(t *testing.T) {
+	route := NewRoute().Schemes("https").Handler(serve(http.StatusOK))
+
+	rw := httptest.NewRecorder()
+	route.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	equal(t, rw.Code, http.StatusNotFound, "plain HTTP request without TLS")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{}
+	rw = httptest.NewRecorder()
+	route.ServeHTTP(rw, req)
+	equal(t, rw.Code, http.StatusOK, "TLS-terminated request")
+}
+
+func writeVars(rw http.ResponseWriter, req *http.Request) {
+	vars := VarsFromContext(req)
+	rw.Write([]byte("id=" + vars.Get("id") + " sub=" + vars.Get("sub")))
+}
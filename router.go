@@ -0,0 +1,281 @@
+package mux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// varsContextKey is the context key under which a Router or a Route stores
+// the Vars collected while matching a request.
+type varsContextKey struct{}
+
+// VarsFromContext returns the Vars collected while routing req. It returns
+// the zero Vars if req was not served by a Router or a Route, or matched a
+// route with no named variables.
+func VarsFromContext(req *http.Request) Vars {
+	vars, _ := req.Context().Value(varsContextKey{}).(Vars)
+	return vars
+}
+
+// wildcardChild pairs a compiled pattern segment with the trie node
+// registered under it. A routeNode can hold several of these side by side,
+// so that sibling patterns differing only in their constraint (e.g.
+// "{id:int}" vs "{slug:[a-z]+}") each keep their own matcher instead of one
+// shadowing the other.
+type wildcardChild struct {
+	matcher *Matcher
+	node    *routeNode
+}
+
+// routeNode is one segment of the registration trie. A node can hold
+// literal children keyed by exact segment text, any number of wildcard
+// children for segments containing '*', '?' or '{name}', and a trailing
+// child that greedily consumes every remaining segment of the path.
+type routeNode struct {
+	literal   map[string]*routeNode
+	wildcards []wildcardChild
+
+	multi     *routeNode
+	multiName string
+
+	methods Method
+	mws     []Middleware
+}
+
+// Router dispatches requests to handlers registered against path patterns,
+// composing the method-based behavior of Method with the Match pattern
+// syntax, trie-style.
+type Router struct {
+	root  *routeNode
+	mws   []Middleware
+	names map[string]string
+}
+
+// NewRouter returns a Router ready for registration.
+func NewRouter() *Router {
+	return &Router{root: &routeNode{}, names: map[string]string{}}
+}
+
+// Use appends mws to the middleware stack applied to routes registered from
+// this point on. Routes already registered are unaffected, except that
+// registering another method for an already registered pattern moves every
+// method of that pattern onto the current stack (see Handle).
+func (rt *Router) Use(mws ...Middleware) {
+	rt.mws = append(rt.mws, mws...)
+}
+
+// Group calls fn with a Router that shares rt's trie and its names (so
+// routes registered inside fn with HandleNamed are reachable through rt's
+// URL, same as any other route registered inside fn is reachable through
+// rt's ServeHTTP) but carries its own copy of rt's middleware stack, so
+// middleware added inside fn (via Use) is scoped to routes registered
+// inside fn.
+func (rt *Router) Group(fn func(r *Router)) {
+	if rt.names == nil {
+		rt.names = map[string]string{}
+	}
+	child := &Router{
+		root:  rt.root,
+		mws:   append([]Middleware(nil), rt.mws...),
+		names: rt.names,
+	}
+	fn(child)
+}
+
+// Handle registers h to serve requests for method against pattern. The
+// middleware installed with Use wraps the route's whole Method, not h
+// alone, so a Middleware such as CORS still observes every method
+// registered for pattern (via Method.Methods) even though only one of
+// them is being registered here. A pattern keeps a single stack for all of
+// its methods: the one in effect at its most recent registration. pattern
+// is a slash-separated sequence of
+// segments, each matched independently using the Match syntax ('*', '?',
+// and '{name}' wildcards); a trailing segment of "*" or "*name" instead
+// matches the rest of the path, including any further slashes, captured
+// under the reserved key "*" or name respectively.
+func (rt *Router) Handle(method, pattern string, h http.Handler) {
+	node := rt.root
+	segments := splitPath(pattern)
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if last && (seg == "*" || strings.HasPrefix(seg, "*")) {
+			name := seg[1:]
+			if name == "" {
+				name = "*"
+			}
+			if node.multi == nil {
+				node.multi = &routeNode{}
+			}
+			node.multiName = name
+			node = node.multi
+			continue
+		}
+		if isPatternSegment(seg) {
+			var child *routeNode
+			for _, wc := range node.wildcards {
+				if wc.matcher.String() == seg {
+					child = wc.node
+					break
+				}
+			}
+			if child == nil {
+				matcher, err := Compile(seg)
+				if err != nil {
+					panic(err)
+				}
+				child = &routeNode{}
+				node.wildcards = append(node.wildcards, wildcardChild{matcher: matcher, node: child})
+			}
+			node = child
+			continue
+		}
+		if node.literal == nil {
+			node.literal = map[string]*routeNode{}
+		}
+		child, ok := node.literal[seg]
+		if !ok {
+			child = &routeNode{}
+			node.literal[seg] = child
+		}
+		node = child
+	}
+	if node.methods == nil {
+		node.methods = Method{}
+	}
+	node.methods[strings.ToUpper(method)] = h
+	node.mws = rt.mws
+}
+
+// Get registers h to serve GET requests for pattern.
+func (rt *Router) Get(pattern string, h http.Handler) { rt.Handle(http.MethodGet, pattern, h) }
+
+// Post registers h to serve POST requests for pattern.
+func (rt *Router) Post(pattern string, h http.Handler) { rt.Handle(http.MethodPost, pattern, h) }
+
+// Put registers h to serve PUT requests for pattern.
+func (rt *Router) Put(pattern string, h http.Handler) { rt.Handle(http.MethodPut, pattern, h) }
+
+// Delete registers h to serve DELETE requests for pattern.
+func (rt *Router) Delete(pattern string, h http.Handler) { rt.Handle(http.MethodDelete, pattern, h) }
+
+// Patch registers h to serve PATCH requests for pattern.
+func (rt *Router) Patch(pattern string, h http.Handler) { rt.Handle(http.MethodPatch, pattern, h) }
+
+// Head registers h to serve HEAD requests for pattern.
+func (rt *Router) Head(pattern string, h http.Handler) { rt.Handle(http.MethodHead, pattern, h) }
+
+// Options registers h to serve OPTIONS requests for pattern.
+func (rt *Router) Options(pattern string, h http.Handler) {
+	rt.Handle(http.MethodOptions, pattern, h)
+}
+
+// ServeHTTP walks the registration trie segment by segment, collecting Vars
+// for any wildcard segments along the way. If the path matches a registered
+// route, the Vars are attached to the request context (retrievable with
+// VarsFromContext) and dispatch is handed off to the matched Method, wrapped
+// in its middleware, which produces the usual 405 + Allow behavior on a
+// method miss. If no route matches the path at all, ServeHTTP responds
+// with 404.
+func (rt *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	node, vars := rt.root.match(strings.ToUpper(req.Method), splitPath(req.URL.Path))
+	if node == nil {
+		http.NotFound(rw, req)
+		return
+	}
+	if len(vars) > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), varsContextKey{}, vars))
+	}
+	Chain(node.mws...)(node.methods).ServeHTTP(rw, req)
+}
+
+// match walks segs against the trie rooted at n, returning the node whose
+// methods should handle the request and the Vars captured along the way,
+// or nil if nothing matches the path at all. Each candidate's own Vars are
+// kept local until it is chosen, rather than written through a shared
+// accumulator, so trying one wildcard sibling and rejecting it in favor of
+// another can never leak the rejected sibling's captures.
+//
+// Among sibling wildcards matching the same segment (see wildcardChild),
+// match prefers whichever one's subtree actually serves method, falling
+// back to the first one that matches the path at all only if none does —
+// that fallback is what lets ServeHTTP still produce the usual 405 + Allow
+// response instead of a 404 when the path is registered under a different
+// method.
+func (n *routeNode) match(method string, segs []string) (*routeNode, Vars) {
+	if len(segs) == 0 {
+		if n.methods != nil {
+			return n, nil
+		}
+		return nil, nil
+	}
+
+	seg, rest := segs[0], segs[1:]
+
+	if child, ok := n.literal[seg]; ok {
+		if found, fv := child.match(method, rest); found != nil {
+			return found, fv
+		}
+	}
+
+	var fallback *routeNode
+	var fallbackVars Vars
+	for _, wc := range n.wildcards {
+		sv := Vars{}
+		if !wc.matcher.Match(seg, &sv) {
+			continue
+		}
+		found, fv := wc.node.match(method, rest)
+		if found == nil {
+			continue
+		}
+		vars := mergeVars(fv, sv)
+		if found.methods[method] != nil {
+			return found, vars
+		}
+		if fallback == nil {
+			fallback, fallbackVars = found, vars
+		}
+	}
+	if fallback != nil {
+		return fallback, fallbackVars
+	}
+
+	if n.multi != nil && n.multi.methods != nil {
+		var vars Vars
+		if n.multiName != "" {
+			vars = Vars{{n.multiName, strings.Join(segs, "/")}}
+		}
+		return n.multi, vars
+	}
+
+	return nil, nil
+}
+
+// mergeVars combines the Vars collected by nested match calls into one,
+// later values in each and in the argument order winning on key collision
+// (which never happens for well-formed patterns, since variable names are
+// unique per route).
+func mergeVars(groups ...Vars) Vars {
+	var out Vars
+	for _, group := range groups {
+		for _, p := range group {
+			out.Set(p.k, p.v)
+		}
+	}
+	return out
+}
+
+// splitPath splits a slash-separated path into its non-empty segments.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// isPatternSegment reports whether seg contains any Match wildcard syntax.
+func isPatternSegment(seg string) bool {
+	return strings.ContainsAny(seg, "*?{")
+}
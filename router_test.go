@@ -0,0 +1,131 @@
+package mux
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/users/{id}", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		vars := VarsFromContext(req)
+		io.WriteString(rw, vars.Get("id"))
+	}))
+	rt.Post("/users", serve(http.StatusCreated))
+	rt.Get("/files/*", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		vars := VarsFromContext(req)
+		io.WriteString(rw, vars.Get("*"))
+	}))
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		body   string
+		allow  string
+	}{
+		{"named var", http.MethodGet, "/users/42", http.StatusOK, "42", ""},
+		{"literal", http.MethodPost, "/users", http.StatusCreated, "", ""},
+		{"method miss", http.MethodDelete, "/users/42", http.StatusMethodNotAllowed, "", "GET, OPTIONS"},
+		{"path miss", http.MethodGet, "/unknown", http.StatusNotFound, "", ""},
+		{"catch-all", http.MethodGet, "/files/a/b/c", http.StatusOK, "a/b/c", ""},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rt.ServeHTTP(rw, req)
+			equal(t, rw.Code, tc.code, "status code")
+			if tc.body != "" {
+				equal(t, rw.Body.String(), tc.body, "body")
+			}
+			if tc.allow != "" {
+				equal(t, rw.Header().Get("Allow"), tc.allow, "allow header")
+			}
+		})
+	}
+}
+
+func TestRouterConstraintSiblings(t *testing.T) {
+	rt := NewRouter()
+	rt.Get("/items/{id:[0-9]+}", serve(http.StatusOK))
+	rt.Post("/items/{slug:[a-z]+}", serve(http.StatusCreated))
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		code   int
+		allow  string
+	}{
+		{"numeric id picks the GET route", http.MethodGet, "/items/123", http.StatusOK, ""},
+		{
+			"numeric id doesn't satisfy the slug constraint, so POST is a method miss on the GET-only id route",
+			http.MethodPost, "/items/123", http.StatusMethodNotAllowed, "GET, OPTIONS",
+		},
+		{"alphabetic slug picks the POST route", http.MethodPost, "/items/abc", http.StatusCreated, ""},
+		{
+			"alphabetic slug doesn't satisfy the id constraint, so GET is a method miss on the POST-only slug route",
+			http.MethodGet, "/items/abc", http.StatusMethodNotAllowed, "OPTIONS, POST",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			rt.ServeHTTP(rw, httptest.NewRequest(tc.method, tc.path, nil))
+			equal(t, rw.Code, tc.code, "status code")
+			if tc.allow != "" {
+				equal(t, rw.Header().Get("Allow"), tc.allow, "allow header")
+			}
+		})
+	}
+}
+
+func TestRouterConstraintSiblingsOverlap(t *testing.T) {
+	// Unlike TestRouterConstraintSiblings, these constraints overlap: "abc"
+	// satisfies both {id:.+} and {slug:[a-z]+}. The first-registered sibling
+	// ({id}, GET-only) must not win just because it matches the path first;
+	// POST has to keep looking and land on {slug}.
+	rt := NewRouter()
+	rt.Get("/widgets/{id:.+}", serve(http.StatusOK))
+	rt.Post("/widgets/{slug:[a-z]+}", serve(http.StatusCreated))
+
+	rw := httptest.NewRecorder()
+	rt.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/widgets/abc", nil))
+	equal(t, rw.Code, http.StatusCreated, "status code")
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				order = append(order, name)
+				h.ServeHTTP(rw, req)
+			})
+		}
+	}
+
+	rt := NewRouter()
+	rt.Use(mw("outer"))
+	rt.Get("/a", serve(200))
+	rt.Group(func(r *Router) {
+		r.Use(mw("inner"))
+		r.Get("/b", serve(200))
+	})
+
+	order = nil
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a", nil))
+	equal(t, order, []string{"outer"}, "route registered before Group")
+
+	order = nil
+	rt.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/b", nil))
+	equal(t, order, []string{"outer", "inner"}, "route registered inside Group")
+}
@@ -0,0 +1,141 @@
+package mux
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MissingVarsError reports that BuildURL was called without values for one
+// or more of a pattern's named variables.
+type MissingVarsError struct {
+	Vars []string
+}
+
+func (err *MissingVarsError) Error() string {
+	return fmt.Sprintf("mux: missing values for variables: %s", strings.Join(err.Vars, ", "))
+}
+
+// BuildURL inverts Match: given a pattern such as "/users/{id}/posts/{slug}"
+// and vars holding "id" and "slug", it produces "/users/42/posts/hello".
+// Patterns containing '*' or '?' are not invertible and are rejected unless
+// vars supplies a value for them under the reserved key "*" or "?"
+// respectively. Every substituted value must not contain '/', except for a
+// trailing "*" or "*name" segment, Router.Handle's catch-all convention,
+// whose value is looked up under "*" or name respectively and substituted
+// as-is, so it may contain '/'.
+func BuildURL(pattern string, vars Vars) (string, error) {
+	var b strings.Builder
+	var missing []string
+
+	i := 0
+	for i < len(pattern) {
+		switch c := pattern[i]; c {
+		case '{':
+			end := strings.IndexByte(pattern[i+1:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("mux: unterminated variable in pattern %q", pattern)
+			}
+			name, _ := splitConstraint(pattern[i+1 : i+1+end])
+			v, ok := vars.lookup(name)
+			if !ok {
+				missing = append(missing, name)
+			} else if strings.Contains(v, "/") {
+				return "", fmt.Errorf("mux: value for %q must not contain '/'", name)
+			} else {
+				b.WriteString(v)
+			}
+			i += end + 2
+		case '*':
+			if name, ok := trailingWildcardName(pattern, i); ok {
+				key := name
+				if key == "" {
+					key = "*"
+				}
+				v, ok := vars.lookup(key)
+				if !ok {
+					return "", fmt.Errorf("mux: pattern %q contains %q; supply a value under the reserved key %q", pattern, pattern[i:], key)
+				}
+				b.WriteString(v)
+				i = len(pattern)
+				continue
+			}
+			v, ok := vars.lookup("*")
+			if !ok {
+				return "", fmt.Errorf("mux: pattern %q contains '*'; supply a value under the reserved key \"*\"", pattern)
+			}
+			if strings.Contains(v, "/") {
+				return "", fmt.Errorf("mux: value for %q must not contain '/'", "*")
+			}
+			b.WriteString(v)
+			i++
+		case '?':
+			key := string(c)
+			v, ok := vars.lookup(key)
+			if !ok {
+				return "", fmt.Errorf("mux: pattern %q contains %q; supply a value under the reserved key %q", pattern, c, key)
+			}
+			if strings.Contains(v, "/") {
+				return "", fmt.Errorf("mux: value for %q must not contain '/'", key)
+			}
+			b.WriteString(v)
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	if len(missing) > 0 {
+		return "", &MissingVarsError{Vars: missing}
+	}
+	return b.String(), nil
+}
+
+// trailingWildcardName reports whether the '*' at index i in pattern opens
+// Router.Handle's trailing catch-all segment: it starts a segment (follows
+// a '/' or begins the pattern) and nothing but its optional name follows to
+// the end of pattern. name is "" for a bare trailing "*", same as Handle's
+// reserved key "*". ok is false for a '*' used anywhere else in pattern,
+// which keeps the ordinary single-segment wildcard semantics.
+func trailingWildcardName(pattern string, i int) (name string, ok bool) {
+	if i != 0 && pattern[i-1] != '/' {
+		return "", false
+	}
+	name = pattern[i+1:]
+	if strings.ContainsRune(name, '/') {
+		return "", false
+	}
+	return name, true
+}
+
+// lookup is like Get but also reports whether key was present at all,
+// distinguishing a missing variable from one explicitly set to "".
+func (vars Vars) lookup(key string) (string, bool) {
+	for _, p := range vars {
+		if p.k == key {
+			return p.v, true
+		}
+	}
+	return "", false
+}
+
+// HandleNamed registers h like Handle, additionally remembering pattern
+// under name so it can be rebuilt later with URL.
+func (rt *Router) HandleNamed(name, method, pattern string, h http.Handler) {
+	rt.Handle(method, pattern, h)
+	if rt.names == nil {
+		rt.names = map[string]string{}
+	}
+	rt.names[name] = pattern
+}
+
+// URL rebuilds the URL path for the route registered under name via
+// HandleNamed, substituting vars with BuildURL.
+func (rt *Router) URL(name string, vars Vars) (string, error) {
+	pattern, ok := rt.names[name]
+	if !ok {
+		return "", fmt.Errorf("mux: no route named %q", name)
+	}
+	return BuildURL(pattern, vars)
+}
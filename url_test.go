@@ -0,0 +1,63 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBuildURL(t *testing.T) {
+	cases := []struct {
+		pattern string
+		vars    Vars
+		want    string
+		wantErr bool
+	}{
+		{"/users/{id}/posts/{slug}", Vars{{"id", "42"}, {"slug", "hello"}}, "/users/42/posts/hello", false},
+		{"/users/{id}", Vars{}, "", true},
+		{"/users/{id}", Vars{{"id", "a/b"}}, "", true},
+		{"/files/*", Vars{{"*", "a/b/c"}}, "/files/a/b/c", false},
+		{"/files/*", Vars{}, "", true},
+		{"/files/*path", Vars{{"path", "a/b/c"}}, "/files/a/b/c", false},
+		{"/files/*path", Vars{}, "", true},
+		{"/files/{name}", Vars{{"name", "report"}}, "/files/report", false},
+	}
+
+	for _, tc := range cases {
+		got, err := BuildURL(tc.pattern, tc.vars)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("BuildURL(%#q, %v) error = %v, wantErr %v", tc.pattern, tc.vars, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr {
+			equal(t, got, tc.want, "built URL")
+		}
+	}
+}
+
+func TestRouterURL(t *testing.T) {
+	rt := NewRouter()
+	rt.HandleNamed("user", http.MethodGet, "/users/{id}", serve(200))
+
+	got, err := rt.URL("user", Vars{{"id", "7"}})
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	equal(t, got, "/users/7", "built URL")
+
+	if _, err := rt.URL("missing", Vars{}); err == nil {
+		t.Fatal("URL: expected error for unnamed route")
+	}
+}
+
+func TestRouterURLInsideGroup(t *testing.T) {
+	rt := NewRouter()
+	rt.Group(func(r *Router) {
+		r.HandleNamed("widget", http.MethodGet, "/widgets/{id}", serve(200))
+	})
+
+	got, err := rt.URL("widget", Vars{{"id", "7"}})
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	equal(t, got, "/widgets/7", "built URL")
+}